@@ -0,0 +1,155 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// MessageID names one of the package's user-visible strings: an error
+// prefix, an inline list join word, a Confirm answer, and so on. Every
+// hard-coded string Ask, Confirm, and List used to print is keyed by one
+// of these, so it can be overridden per Catalog or per Question.
+type MessageID int
+
+const (
+	// AskOnError is the prompt Ask falls back to (via Question.Responses)
+	// after a rejected answer, when the caller hasn't set one explicitly.
+	AskOnError MessageID = iota
+	// AskOnInvalid formats the error Ask prints when an answer is rejected.
+	AskOnInvalid
+	// ConfirmYes and ConfirmNo are the words Confirm's default resolves to.
+	ConfirmYes
+	ConfirmNo
+	// InlineJoin is the text List(..., Inline, nil) puts before the last
+	// item: "a or b" for two items, "a, b,  or c" (the ", " separator
+	// followed by the join) for more.
+	InlineJoin
+	// DefaultPrefix formats a prompt's default-value indicator.
+	DefaultPrefix
+)
+
+// Catalog maps MessageIDs to their text, with overlays per language.Tag
+// layered over a base table, along the lines of
+// golang.org/x/text/message/catalog. Language resolution uses the same
+// best-match semantics as that package, so an overlay registered for
+// "es" also serves "es-MX".
+type Catalog struct {
+	base     map[MessageID]string
+	overlays map[language.Tag]map[MessageID]string
+	tags     []language.Tag
+	matcher  language.Matcher
+}
+
+// NewCatalog creates a Catalog whose base table is consulted when no
+// overlay (or no entry within a matched overlay) has a message.
+func NewCatalog(base map[MessageID]string) *Catalog {
+	return &Catalog{
+		base:     base,
+		overlays: map[language.Tag]map[MessageID]string{},
+	}
+}
+
+// Set installs (or replaces) the message table for tag.
+func (c *Catalog) Set(tag language.Tag, table map[MessageID]string) {
+	c.overlays[tag] = table
+	c.tags = c.tags[:0]
+	for t := range c.overlays {
+		c.tags = append(c.tags, t)
+	}
+	if len(c.tags) == 0 {
+		c.matcher = nil
+		return
+	}
+	c.matcher = language.NewMatcher(c.tags)
+}
+
+// message resolves id for tag, checking the best-matching overlay before
+// falling back to the base table and finally to def.
+func (c *Catalog) message(tag language.Tag, id MessageID, def string) string {
+	if c == nil {
+		return def
+	}
+	if c.matcher != nil {
+		_, index, conf := c.matcher.Match(tag)
+		if conf != language.No {
+			if s, ok := c.overlays[c.tags[index]][id]; ok {
+				return s
+			}
+		}
+	}
+	if s, ok := c.base[id]; ok {
+		return s
+	}
+	return def
+}
+
+var (
+	catalogMu       sync.RWMutex
+	packageCatalog  = defaultCatalog()
+	packageLanguage = language.English
+)
+
+// SetCatalog installs c as the package-wide message catalog used by Ask,
+// Confirm, and List whenever a Question doesn't override it.
+func SetCatalog(c *Catalog) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	packageCatalog = c
+}
+
+// SetLanguage installs tag as the package-wide language used to resolve
+// messages whenever a Question doesn't override it.
+func SetLanguage(tag language.Tag) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	packageLanguage = tag
+}
+
+// msg resolves MessageID id to text, preferring q's Catalog/Language (if
+// set) over the package defaults, and formats it with args when given.
+func msg(q *Question, id MessageID, def string, args ...interface{}) string {
+	catalogMu.RLock()
+	cat, lang := packageCatalog, packageLanguage
+	catalogMu.RUnlock()
+	if q != nil {
+		if q.Catalog != nil {
+			cat = q.Catalog
+		}
+		if q.Language != (language.Tag{}) {
+			lang = q.Language
+		}
+	}
+	text := cat.message(lang, id, def)
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// defaultCatalog builds the catalog goline ships with: English as the
+// base table, plus a Spanish overlay as a starting point for additional
+// locales.
+func defaultCatalog() *Catalog {
+	c := NewCatalog(map[MessageID]string{
+		AskOnError:    "",
+		AskOnInvalid:  "Error: %s\n",
+		ConfirmYes:    "yes",
+		ConfirmNo:     "no",
+		InlineJoin:    " or ",
+		DefaultPrefix: "|%s|%s",
+	})
+	c.Set(language.Spanish, map[MessageID]string{
+		AskOnInvalid:  "Error: %s\n",
+		ConfirmYes:    "sí",
+		ConfirmNo:     "no",
+		InlineJoin:    " o ",
+		DefaultPrefix: "|%s|%s",
+	})
+	return c
+}
@@ -0,0 +1,97 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %s", err)
+	}
+	os.Stdout = w
+	f()
+	w.Close()
+	os.Stdout = old
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read captured stdout: %s", err)
+	}
+	return string(out)
+}
+
+func TestMsgFallsBackToDefault(t *testing.T) {
+	if got := msg(nil, ConfirmYes, "yes"); got != "yes" {
+		t.Fatalf("msg(nil, ConfirmYes, ...) = %q, want %q", got, "yes")
+	}
+}
+
+func TestSetLanguageChangesPackageMessages(t *testing.T) {
+	defer SetLanguage(language.English)
+	SetLanguage(language.Spanish)
+	if got := msg(nil, ConfirmYes, "yes"); got != "sí" {
+		t.Fatalf("msg(nil, ConfirmYes, ...) under Spanish = %q, want %q", got, "sí")
+	}
+	if got := msg(nil, InlineJoin, " or "); got != " o " {
+		t.Fatalf("msg(nil, InlineJoin, ...) under Spanish = %q, want %q", got, " o ")
+	}
+}
+
+func TestQuestionLanguageOverridesPackage(t *testing.T) {
+	defer SetLanguage(language.English)
+	SetLanguage(language.English)
+	q := &Question{Language: language.Spanish}
+	if got := msg(q, ConfirmYes, "yes"); got != "sí" {
+		t.Fatalf("msg(q, ConfirmYes, ...) with q.Language=Spanish = %q, want %q", got, "sí")
+	}
+}
+
+func TestConfirmHonorsPerCallLanguageOverride(t *testing.T) {
+	old := os.Stdin
+	defer func() { os.Stdin = old }()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %s", err)
+	}
+	os.Stdin = r
+	w.WriteString("y\n")
+	w.Close()
+
+	out := captureStdout(t, func() {
+		Confirm("Continue? ", true, func(q *Question) { q.Language = language.Spanish })
+	})
+	if !strings.Contains(out, "sí") {
+		t.Fatalf("Confirm output %q does not contain Spanish default %q", out, "sí")
+	}
+}
+
+func TestSetCatalogChangesListInlineJoin(t *testing.T) {
+	defer SetCatalog(defaultCatalog())
+	items := []string{"a", "b", "c"}
+
+	before := captureStdout(t, func() { List(items, Inline, nil) })
+	if !strings.Contains(before, " or ") {
+		t.Fatalf("List Inline output %q does not contain default join %q", before, " or ")
+	}
+
+	custom := NewCatalog(map[MessageID]string{InlineJoin: " and "})
+	SetCatalog(custom)
+	after := captureStdout(t, func() { List(items, Inline, nil) })
+	if !strings.Contains(after, " and ") {
+		t.Fatalf("List Inline output %q does not contain overridden join %q", after, " and ")
+	}
+	if strings.Contains(after, " or ") {
+		t.Fatalf("List Inline output %q still contains default join after SetCatalog", after)
+	}
+}
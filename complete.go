@@ -0,0 +1,62 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// StringSetCompleter builds a Question.Complete hook that offers the
+// members of set whose text starts with the current buffer. It's the
+// same vocabulary In(set) validates against, so a Question can offer and
+// enforce the same StringSet at once.
+func StringSetCompleter(set StringSet) func(prefix string) []string {
+	return func(prefix string) []string {
+		var out []string
+		for _, s := range set {
+			if strings.HasPrefix(s, prefix) {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+}
+
+// FileCompleter builds a Question.Complete hook that offers filesystem
+// paths under base (the current directory if base is nil or empty)
+// matching the current buffer. Directories are suggested with a trailing
+// "/" so completion can continue into them.
+func FileCompleter(base *string) func(prefix string) []string {
+	root := "."
+	if base != nil && *base != "" {
+		root = *base
+	}
+	return func(prefix string) []string {
+		dir, namePrefix := "", prefix
+		if i := strings.LastIndexByte(prefix, '/'); i >= 0 {
+			dir, namePrefix = prefix[:i+1], prefix[i+1:]
+		}
+		entries, err := ioutil.ReadDir(filepath.Join(root, dir))
+		if err != nil {
+			return nil
+		}
+		var out []string
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), namePrefix) {
+				continue
+			}
+			match := dir + entry.Name()
+			if entry.IsDir() {
+				match += "/"
+			}
+			out = append(out, match)
+		}
+		sort.Strings(out)
+		return out
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestStringSetCompleter(t *testing.T) {
+	complete := StringSetCompleter(StringSet{"apple", "apricot", "banana"})
+	if got := complete("ap"); !reflect.DeepEqual(got, []string{"apple", "apricot"}) {
+		t.Fatalf("complete(%q) = %v, want %v", "ap", got, []string{"apple", "apricot"})
+	}
+	if got := complete("b"); !reflect.DeepEqual(got, []string{"banana"}) {
+		t.Fatalf("complete(%q) = %v, want %v", "b", got, []string{"banana"})
+	}
+	if got := complete("z"); got != nil {
+		t.Fatalf("complete(%q) = %v, want nil", "z", got)
+	}
+}
+
+func TestFileCompleter(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"apple.txt", "apricot.txt", "banana.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatalf("WriteFile: %s", err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "appdir"), 0o755); err != nil {
+		t.Fatalf("Mkdir: %s", err)
+	}
+
+	complete := FileCompleter(&dir)
+	got := complete("ap")
+	sort.Strings(got)
+	want := []string{"appdir/", "apple.txt", "apricot.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("complete(%q) = %v, want %v", "ap", got, want)
+	}
+
+	if got := complete("zzz"); got != nil {
+		t.Fatalf("complete(%q) = %v, want nil", "zzz", got)
+	}
+}
+
+func TestFileCompleterSubdirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatalf("Mkdir: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "file.txt"), nil, 0o644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	complete := FileCompleter(&dir)
+	got := complete("sub/fi")
+	want := []string{"sub/file.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("complete(%q) = %v, want %v", "sub/fi", got, want)
+	}
+}
+
+func TestFileCompleterDefaultsToCurrentDir(t *testing.T) {
+	complete := FileCompleter(nil)
+	if complete == nil {
+		t.Fatal("FileCompleter(nil) returned a nil completer")
+	}
+	// go.mod always exists in the repo root, which is the working
+	// directory tests run from.
+	got := complete("go.m")
+	want := []string{"go.mod"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("complete(%q) = %v, want %v", "go.m", got, want)
+	}
+}
+
+func TestLongestCommonPrefix(t *testing.T) {
+	cases := []struct {
+		in   []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"apple"}, "apple"},
+		{[]string{"apple", "apricot"}, "ap"},
+		{[]string{"apple", "banana"}, ""},
+		{[]string{"goline", "goline"}, "goline"},
+	}
+	for _, c := range cases {
+		if got := longestCommonPrefix(c.in); got != c.want {
+			t.Fatalf("longestCommonPrefix(%v) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const defaultEditorTrigger = "e"
+
+// Editor prompts by handing the user's terminal to $EDITOR (or $VISUAL,
+// falling back to vi/notepad) for multi-line or long-form answers: commit
+// messages, PR bodies, config blobs. The current default (if any) seeds
+// the temp file the editor opens; the saved contents become the answer
+// and run through the same validation pipeline Ask uses.
+//
+// Hitting enter with an empty response, or typing the trigger word
+// (q.EditorTrigger, "e" by default), both open the editor.
+func Editor(dest *string, message string, config func(*Question)) error {
+	return Ask(dest, message, func(q *Question) {
+		q.Editor = true
+		q.EditorTrigger = defaultEditorTrigger
+		if config != nil {
+			config(q)
+		}
+	})
+}
+
+// runEditor opens $EDITOR on a temp file seeded with q.Default, wired to
+// the controlling terminal (via openTTY) so it works even though os.Stdin
+// is being read through a bufio.Reader elsewhere in Ask. It returns the
+// file's contents, with all trailing CR/LF characters (such as the newline
+// nearly every editor adds on save) trimmed, after the editor exits.
+func (q *Question) runEditor() (string, error) {
+	f, err := ioutil.TempFile("", "goline-")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	if _, err := f.WriteString(q.Default); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	parts := strings.Fields(resolveEditor())
+	if len(parts) == 0 {
+		parts = []string{"vi"}
+	}
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+
+	if in, out, err := openTTY(); err == nil {
+		defer in.Close()
+		if out != in {
+			defer out.Close()
+		}
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = in, out, out
+	} else {
+		// No controlling terminal available (e.g. stdin/stdout were
+		// redirected). Falling back to os.Stdin here re-creates the race
+		// with Ask's bufio.Reader that wiring to the terminal avoids, but
+		// it's the best we can do without one.
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("goline: %s exited: %s", parts[0], err)
+	}
+
+	out, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+func resolveEditor() string {
+	if e := os.Getenv("EDITOR"); e != "" {
+		return e
+	}
+	if e := os.Getenv("VISUAL"); e != "" {
+		return e
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// openTTY opens the controlling terminal directly, bypassing os.Stdin/
+// os.Stdout, so a spawned editor doesn't contend with Ask's own buffered
+// reads. On Windows, where there's no /dev/tty, it opens the console's
+// input and output handles separately.
+func openTTY() (in, out *os.File, err error) {
+	if runtime.GOOS == "windows" {
+		in, err = os.OpenFile("CONIN$", os.O_RDWR, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		out, err = os.OpenFile("CONOUT$", os.O_RDWR, 0)
+		if err != nil {
+			in.Close()
+			return nil, nil, err
+		}
+		return in, out, nil
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tty, tty, nil
+}
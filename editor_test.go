@@ -0,0 +1,66 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeEditor writes a shell script standing in for $EDITOR that appends
+// line to whatever file it's invoked on ($1), and returns its path.
+func fakeEditor(t *testing.T, line string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fake-editor.sh")
+	script := fmt.Sprintf("#!/bin/sh\nprintf '%s\\n' >> \"$1\"\n", line)
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	return path
+}
+
+func TestRunEditorTrimsTrailingNewline(t *testing.T) {
+	old, hadOld := os.LookupEnv("EDITOR")
+	defer func() {
+		if hadOld {
+			os.Setenv("EDITOR", old)
+		} else {
+			os.Unsetenv("EDITOR")
+		}
+	}()
+	os.Setenv("EDITOR", fakeEditor(t, "hello"))
+
+	q := &Question{}
+	got, err := q.runEditor()
+	if err != nil {
+		t.Fatalf("runEditor: %s", err)
+	}
+	if got != "hello" {
+		t.Fatalf("runEditor() = %q, want %q", got, "hello")
+	}
+}
+
+func TestRunEditorSeedsDefault(t *testing.T) {
+	old, hadOld := os.LookupEnv("EDITOR")
+	defer func() {
+		if hadOld {
+			os.Setenv("EDITOR", old)
+		} else {
+			os.Unsetenv("EDITOR")
+		}
+	}()
+	os.Setenv("EDITOR", fakeEditor(t, "more"))
+
+	q := &Question{Default: "existing\n"}
+	got, err := q.runEditor()
+	if err != nil {
+		t.Fatalf("runEditor: %s", err)
+	}
+	if got != "existing\nmore" {
+		t.Fatalf("runEditor() = %q, want %q", got, "existing\nmore")
+	}
+}
@@ -7,85 +7,80 @@
  *  Package:     goline
  *  Author:      Bryan Matsuo <bmatsuo@soe.ucsc.edu>
  *  Created:     Sat Aug 13 02:28:54 PDT 2011
- *  Description: 
+ *  Description:
  */
 
-//  Package goline is a command line interfacing (prompting) library inspired
-//  by Ruby's HighLine.
+// Package goline is a command line interfacing (prompting) library inspired
+// by Ruby's HighLine.
 //
-//  Differences for HighLine users:
+// Differences for HighLine users:
 //
-//      - To be more Go-ish, where HighLine uses the term "strip", the package
-//        uses "trim".
-//  
-//      - Instead of an Agree(question,...) function, the package provides a
-//        function `Confirm(question, yesorno) bool`. This is because the
-//        author things the term "agree" implies the desire of a positive
-//        response to the question ("yes"). The idea is to set up Confirm with
-//        positive language and believed truth value of that statement.
-//              if cont := false; !Confirm("Continue anyway? ", cont, nil) {
-//                  os.Exit(1)
-//              }
-//              // Continue.
-//              // ...
-//        But Confirm is flexible enough to be used in other manners.
+//   - To be more Go-ish, where HighLine uses the term "strip", the package
+//     uses "trim".
+//
+//   - Instead of an Agree(question,...) function, the package provides a
+//     function `Confirm(question, yesorno) bool`. This is because the
+//     author things the term "agree" implies the desire of a positive
+//     response to the question ("yes"). The idea is to set up Confirm with
+//     positive language and believed truth value of that statement.
+//     if cont := false; !Confirm("Continue anyway? ", cont, nil) {
+//     os.Exit(1)
+//     }
+//     // Continue.
+//     // ...
+//     But Confirm is flexible enough to be used in other manners.
 package goline
 
 import (
-    "reflect"
-    "strings"
-    "unicode"
-    "bufio"
-    "utf8"
-    "fmt"
-    "os"
+	"bufio"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
-//  Returns the index i of the longest terminal substring s[i:] such that f
-//  returns true for all runes in s[i:]. Returns -1 if there is no such i.
-func stringSuffixIndexFunc(s string, f func(c int) bool) (i int) {
-    var hasSuffix bool
-    i = strings.LastIndexFunc(s, func(c int) (done bool) {
-        if done = !f(c); !hasSuffix {
-            hasSuffix = !done
-        }
-        return
-    })
-    if i++; !hasSuffix {
-        i = -1
-    }
-    return
+// Returns the index i of the longest terminal substring s[i:] such that f
+// returns true for all runes in s[i:]. Returns -1 if there is no such i.
+func stringSuffixIndexFunc(s string, f func(c rune) bool) (i int) {
+	var hasSuffix bool
+	i = strings.LastIndexFunc(s, func(c rune) (done bool) {
+		if done = !f(c); !hasSuffix {
+			hasSuffix = !done
+		}
+		return
+	})
+	if i++; !hasSuffix {
+		i = -1
+	}
+	return
 }
 
-//  Return the suffix string corresponding to the same call to
-//  stringSuffixIndexFunc.
-func stringSuffixFunc(s string, f func(c int) bool) (suff string) {
-    if i := stringSuffixIndexFunc(s, f); i >= 0 {
-        suff = s[i:]
-    }
-    return
+// Return the suffix string corresponding to the same call to
+// stringSuffixIndexFunc.
+func stringSuffixFunc(s string, f func(c rune) bool) (suff string) {
+	if i := stringSuffixIndexFunc(s, f); i >= 0 {
+		suff = s[i:]
+	}
+	return
 }
 
-func Say(msg string) (int, os.Error) {
-    if c, _ := utf8.DecodeLastRuneInString(msg); unicode.IsSpace(c) {
-        return fmt.Print(msg)
-    }
-    return fmt.Println(msg)
+func Say(msg string) (int, error) {
+	if c, _ := utf8.DecodeLastRuneInString(msg); unicode.IsSpace(c) {
+		return fmt.Print(msg)
+	}
+	return fmt.Println(msg)
 }
 
-func SayTrimmed(msg string) (int, os.Error) {
-    return Say(strings.TrimRightFunc(msg, unicode.IsSpace))
+func SayTrimmed(msg string) (int, error) {
+	return Say(strings.TrimRightFunc(msg, unicode.IsSpace))
 }
 
 type Stringer interface {
-    String() string
+	String() string
 }
 
-var (
-    zeroStringer Stringer
-    typeStringer = reflect.TypeOf(zeroStringer)
-)
-
 type simpleString string
 
 func (s simpleString) String() string { return string(s) }
@@ -93,271 +88,294 @@ func (s simpleString) String() string { return string(s) }
 var zeroSimpleString simpleString
 
 func makeStringer(s interface{}) Stringer {
-    switch s.(type) {
-    case string:
-        return simpleString(s.(string))
-    case Stringer:
-        return s.(Stringer)
-    default:
-        panic("Value must be type 'string' or 'Stringer'")
-    }
-    return zeroStringer
+	switch s.(type) {
+	case string:
+		return simpleString(s.(string))
+	case Stringer:
+		return s.(Stringer)
+	default:
+		panic("Value must be type 'string' or 'Stringer'")
+	}
 }
 
 type ListMode uint
 
 const (
-    ColumnsAcross ListMode = iota
-    ColumnsDown
-    Inline
-    Rows
+	ColumnsAcross ListMode = iota
+	ColumnsDown
+	Inline
+	Rows
 )
 
-func List(items interface{}, mode ListMode, option interface{}) {
-    ival := reflect.ValueOf(items)
-    itype := ival.Type()
-    if k := itype.Kind(); k != reflect.Slice {
-        panic(os.NewError("List given non-Slice types."))
-    }
-    strs := make([]string, ival.Len())
-    for i := range strs {
-        v := ival.Index(i).Interface()
-        switch v.(type) {
-        case Stringer:
-            strs[i] = v.(Stringer).String()
-        case string:
-            strs[i] = v.(string)
-        default:
-            panic(os.NewError("List items contain non-string, non-Stringer item"))
-        }
-    }
-    switch mode {
-    case ColumnsAcross:
-        fallthrough
-    case ColumnsDown:
-        wrap := 80
-        switch option.(type) {
-        case nil:
-        case int:
-            wrap = option.(int)
-        default:
-            panic(os.NewError("List option of unacceptable type"))
-        }
+// stringifyList converts a Slice of string or Stringer values into a slice
+// of strings. It is shared by List and the interactive menu prompts
+// (Select, MultiSelect) so item rendering stays consistent everywhere.
+func stringifyList(items interface{}) []string {
+	ival := reflect.ValueOf(items)
+	itype := ival.Type()
+	if k := itype.Kind(); k != reflect.Slice {
+		panic(fmt.Errorf("goline: List given non-Slice type %s", itype))
+	}
+	strs := make([]string, ival.Len())
+	for i := range strs {
+		v := ival.Index(i).Interface()
+		switch v.(type) {
+		case Stringer:
+			strs[i] = v.(Stringer).String()
+		case string:
+			strs[i] = v.(string)
+		default:
+			panic(fmt.Errorf("goline: List items contain non-string, non-Stringer item"))
+		}
+	}
+	return strs
+}
 
-        var width int
-        for i := range strs {
-            if n := len(strs[i]); n > width {
-                width = n
-            }
-        }
+// columnRows lays strs out into wrap-width columns (ColumnsAcross order, or
+// ColumnsDown order when down is true), returning one joined, space-padded
+// string per row. It's shared by List and the raw-mode candidate listing in
+// readLineRaw, which can't use List directly because it needs "\r\n" line
+// endings instead of Say's plain "\n".
+func columnRows(strs []string, wrap int, down bool) []string {
+	var width int
+	for i := range strs {
+		if n := len(strs[i]); n > width {
+			width = n
+		}
+	}
 
-        n := len(strs)
-        ncols := (wrap + 1) / (width + 1)
+	n := len(strs)
+	ncols := (wrap + 1) / (width + 1)
 
-        if ncols <= 1 {
-            // Just print rows if no more than 1 column fits.
-            for i := range strs {
-                SayTrimmed(strs[i])
-            }
-            break
-        }
+	if ncols <= 1 {
+		rows := make([]string, n)
+		copy(rows, strs)
+		return rows
+	}
 
-        nrows := (n + ncols - 1) / ncols
+	nrows := (n + ncols - 1) / ncols
 
-        sfmt := fmt.Sprintf("%%-%ds", width)
-        for i := range strs {
-            strs[i] = fmt.Sprintf(sfmt, strs[i])
-        }
+	sfmt := fmt.Sprintf("%%-%ds", width)
+	padded := make([]string, n)
+	for i := range strs {
+		padded[i] = fmt.Sprintf(sfmt, strs[i])
+	}
 
-        switch mode {
-        case ColumnsAcross:
-            for i := 0; i < n; i += ncols {
-                end := i + ncols
-                if end > n {
-                    end = n
-                }
-                row := strs[i:end]
-                SayTrimmed(strings.Join(row, " "))
-            }
-        case ColumnsDown:
-            for i := 0; i < nrows; i++ {
-                var row []string
-                for j := 0; j < ncols; j++ {
-                    index := j*nrows + i
-                    if index >= n {
-                        break
-                    }
-                    row = append(row, strs[index])
-                }
-                SayTrimmed(strings.Join(row, " "))
-            }
-        }
-    case Inline:
-        n := len(strs)
-        if n == 1 {
-            SayTrimmed(strs[0])
-            break
-        }
-        join := " or "
-        switch option.(type) {
-        case nil:
-        case string:
-            join = option.(string)
-        default:
-            panic(os.NewError("List option of unacceptable type"))
-        }
-        if n == 2 {
-            Say(strings.Join([]string{strs[n-2], join, strs[n-2], "\n"}, ""))
-            break
-        }
-        strs[n-1] = join + strs[n-1]
-        SayTrimmed(strings.Join(strs, ", "))
-    case Rows:
-        for i := range strs {
-            SayTrimmed(strs[i])
-        }
-    default:
-        panic(os.NewError("Unknown mode"))
-    }
+	var rows []string
+	if !down {
+		for i := 0; i < n; i += ncols {
+			end := i + ncols
+			if end > n {
+				end = n
+			}
+			rows = append(rows, strings.Join(padded[i:end], " "))
+		}
+	} else {
+		for i := 0; i < nrows; i++ {
+			var row []string
+			for j := 0; j < ncols; j++ {
+				index := j*nrows + i
+				if index >= n {
+					break
+				}
+				row = append(row, padded[index])
+			}
+			rows = append(rows, strings.Join(row, " "))
+		}
+	}
+	return rows
 }
 
-//  Prompt the user for text input. The result is stored in dest, which must
-//  be a pointer to a native Go type (int, uint16, string, float32, ...).
-//  Slice types are not currently supported. List input must be done with a
-//  *string destination and post-processing.
-func Ask(dest interface{}, msg string, config func(*Question)) (e os.Error) {
-    var q *Question
-    defer func() {
-        if err := recover(); err != nil {
-            switch err.(type) {
-            case os.Error:
-                // Call a panic method...
-                if q.Panic != nil {
-                    q.Panic(err.(os.Error))
-                }
-            default:
-                panic(err)
-            }
-        }
-    }()
-    if k := reflect.TypeOf(dest).Kind(); k != reflect.Ptr && k != reflect.Slice {
-        panicUnrecoverable(fmt.Errorf("Ask(...) requires a Ptr type, not %s", k.String()))
-        return
-    } else if k == reflect.Slice {
-        panicUnrecoverable(fmt.Errorf("Ask(...) can not currently assign to slices."))
-        return
-    }
+func List(items interface{}, mode ListMode, option interface{}) {
+	strs := stringifyList(items)
+	switch mode {
+	case ColumnsAcross:
+		fallthrough
+	case ColumnsDown:
+		wrap := 80
+		switch option.(type) {
+		case nil:
+		case int:
+			wrap = option.(int)
+		default:
+			panic(fmt.Errorf("goline: List option of unacceptable type"))
+		}
+		for _, row := range columnRows(strs, wrap, mode == ColumnsDown) {
+			SayTrimmed(row)
+		}
+	case Inline:
+		n := len(strs)
+		if n == 1 {
+			SayTrimmed(strs[0])
+			break
+		}
+		join := msg(nil, InlineJoin, " or ")
+		switch option.(type) {
+		case nil:
+		case string:
+			join = option.(string)
+		default:
+			panic(fmt.Errorf("goline: List option of unacceptable type"))
+		}
+		if n == 2 {
+			Say(strings.Join([]string{strs[n-2], join, strs[n-1], "\n"}, ""))
+			break
+		}
+		strs[n-1] = join + strs[n-1]
+		SayTrimmed(strings.Join(strs, ", "))
+	case Rows:
+		for i := range strs {
+			SayTrimmed(strs[i])
+		}
+	default:
+		panic(fmt.Errorf("goline: unknown ListMode %d", mode))
+	}
+}
 
-    var t Type
-    switch dest.(type) {
-    case *uint:
-        t = Uint
-    case *uint8:
-        t = Uint
-    case *uint16:
-        t = Uint
-    case *uint32:
-        t = Uint
-    case *uint64:
-        t = Uint
-    case *int:
-        t = Int
-    case *int8:
-        t = Int
-    case *int16:
-        t = Int
-    case *int32:
-        t = Int
-    case *int64:
-        t = Int
-    case *float32:
-        t = Float
-    case *float64:
-        t = Float
-    case *string:
-        t = String
-    default:
-        fmt.Errorf("Unusable destination")
-    }
-    q = newQuestion(t)
-    q.Question = msg
-    if config != nil {
-        config(q)
-    }
+// Prompt the user for text input. The result is stored in dest, which must
+// be a pointer to a native Go type (int, uint16, string, float32, ...).
+// Slice types are not currently supported. List input must be done with a
+// *string destination and post-processing.
+func Ask(dest interface{}, message string, config func(*Question)) (e error) {
+	var q *Question
+	defer func() {
+		if err := recover(); err != nil {
+			switch err.(type) {
+			case error:
+				// Call a panic method...
+				if q.Panic != nil {
+					q.Panic(err.(error))
+				}
+			default:
+				panic(err)
+			}
+		}
+	}()
+	if k := reflect.TypeOf(dest).Kind(); k != reflect.Ptr && k != reflect.Slice {
+		panicUnrecoverable(fmt.Errorf("Ask(...) requires a Ptr type, not %s", k.String()))
+		return
+	} else if k == reflect.Slice {
+		panicUnrecoverable(fmt.Errorf("Ask(...) can not currently assign to slices."))
+		return
+	}
 
-    if err := q.tryFirstAnswer(); err == nil && q.val != nil {
-        if err := q.setDest(dest); err != nil {
-            panicUnrecoverable(err)
-            q.val = nil
-        }
-        return
-    }
+	var t Type
+	switch dest.(type) {
+	case *uint:
+		t = Uint
+	case *uint8:
+		t = Uint
+	case *uint16:
+		t = Uint
+	case *uint32:
+		t = Uint
+	case *uint64:
+		t = Uint
+	case *int:
+		t = Int
+	case *int8:
+		t = Int
+	case *int16:
+		t = Int
+	case *int32:
+		t = Int
+	case *int64:
+		t = Int
+	case *float32:
+		t = Float
+	case *float64:
+		t = Float
+	case *string:
+		t = String
+	default:
+		panicUnrecoverable(fmt.Errorf("goline: unusable destination type %T", dest))
+		return
+	}
+	q = newQuestion(t)
+	q.Question = message
+	if config != nil {
+		config(q)
+	}
 
-    prompt := msg
-    contFunc := func(err os.Error) {
-        Say(fmt.Sprintf("Error: %s\n", err.String()))
-        prompt = q.Responses[AskOnError]
-    }
-    r := bufio.NewReader(os.Stdin)
-    for {
-        tail := stringSuffixFunc(prompt, unicode.IsSpace)
-        Say(prompt + q.defaultString(tail))
-        var resp []byte
-        for cont := true; cont; {
-            s, isPrefix, err := r.ReadLine()
-            if err != nil {
-                panicUnrecoverable(err)
-                return
-            }
-            resp = append(resp, s...)
-            cont = isPrefix
-        }
-        if err := q.parse(string(resp)); err != nil {
-            panicUnrecoverable(err)
-            contFunc(err)
-            continue
-        }
+	if err := q.tryFirstAnswer(); err == nil && q.val != nil {
+		if err := q.setDest(dest); err != nil {
+			panicUnrecoverable(err)
+			q.val = nil
+		}
+		return
+	}
 
-        // Cast the result from a wide (e.g. 64bit) type to the desired type.
-        // This should not fail under any normal circumstances, so failure
-        // should break the loop.
-        if err := q.setDest(dest); err != nil {
-            panicUnrecoverable(err)
-            contFunc(err)
-            continue
-        }
-        break
-    }
-    return
+	prompt := message
+	contFunc := func(err error) {
+		Say(msg(q, AskOnInvalid, "Error: %s\n", err.Error()))
+		if resp, ok := q.Responses[AskOnError]; ok {
+			prompt = resp
+		} else {
+			prompt = msg(q, AskOnError, "")
+		}
+	}
+	r := bufio.NewReader(os.Stdin)
+	for {
+		tail := stringSuffixFunc(prompt, unicode.IsSpace)
+		shown := prompt + q.defaultString(tail)
+		Say(shown)
+		resp, err := q.readLine(r, shown)
+		if err != nil {
+			panicUnrecoverable(err)
+			return
+		}
+		if q.Editor && t == String && (resp == "" || resp == q.EditorTrigger) {
+			edited, err := q.runEditor()
+			if err != nil {
+				contFunc(err)
+				continue
+			}
+			resp = edited
+		}
+		if err := q.parse(resp); err != nil {
+			contFunc(err)
+			continue
+		}
+
+		// Cast the result from a wide (e.g. 64bit) type to the desired type.
+		// This should not fail under any normal circumstances, so failure
+		// should break the loop.
+		if err := q.setDest(dest); err != nil {
+			panicUnrecoverable(err)
+			contFunc(err)
+			continue
+		}
+		break
+	}
+	return
 }
 
 func Confirm(question string, yes bool, config func(*Question)) bool {
-    def := "no"
-    if yes {
-        def = "yes"
-    }
-
-    var okstr string
-    var err os.Error
-    Ask(&okstr, question, func(q *Question) {
-        q.Default = def
-        q.In(StringSet{"yes", "y", "no", "n"})
-        if config != nil {
-            config(q)
-        }
-        if q.Panic != nil {
-            f := q.Panic
-            q.Panic = func(e os.Error) {
-                err = e
-                f(e)
-            }
-        }
-    })
-    if err != nil {
-        return false
-    }
-    if okstr[0] == 'y' {
-        return true
-    }
-    return false
+	var okstr string
+	var err error
+	Ask(&okstr, question, func(q *Question) {
+		if config != nil {
+			config(q)
+		}
+		def := msg(q, ConfirmNo, "no")
+		if yes {
+			def = msg(q, ConfirmYes, "yes")
+		}
+		q.Default = def
+		q.In(StringSet{"yes", "y", "no", "n"})
+		if q.Panic != nil {
+			f := q.Panic
+			q.Panic = func(e error) {
+				err = e
+				f(e)
+			}
+		}
+	})
+	if err != nil {
+		return false
+	}
+	if okstr[0] == 'y' {
+		return true
+	}
+	return false
 }
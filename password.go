@@ -0,0 +1,160 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Password prompts for a line of input without echoing it back to the
+// terminal (or echoing a mask rune, via Question.EchoMask, per character
+// typed). It otherwise behaves exactly like Ask on a *string: defaults,
+// validators, and q.Panic all apply. To require a second Password prompt
+// match the first, register a Question.Validate hook on the confirmation
+// prompt rather than q.In(StringSet{first}) — In's rejection message
+// echoes the expected value back via Say, which would print the first
+// password in cleartext on a mismatch.
+//
+//	var pass1 string
+//	Password(&pass1, "Password: ", nil)
+//	var pass2 string
+//	Password(&pass2, "Confirm password: ", func(q *Question) {
+//		q.Validate(func(q *Question, s string) error {
+//			if s != pass1 {
+//				return fmt.Errorf("goline: passwords do not match")
+//			}
+//			return nil
+//		})
+//	})
+func Password(dest *string, message string, config func(*Question)) error {
+	return Ask(dest, message, func(q *Question) {
+		q.Echo = false
+		if config != nil {
+			config(q)
+		}
+	})
+}
+
+// wantsRawIO reports whether q's settings require raw terminal mode to
+// honor (plain line buffering always echoes what's typed and can't
+// intercept individual keys like TAB).
+func (q *Question) wantsRawIO() bool {
+	return !q.Echo || q.EchoMask != 0 || q.Complete != nil
+}
+
+func echoChar(q *Question, c byte) {
+	if q.EchoMask != 0 {
+		fmt.Print(string(q.EchoMask))
+	} else if q.Echo {
+		os.Stdout.Write([]byte{c})
+	}
+}
+
+// readLineRaw reads a single line directly from the controlling terminal
+// in raw mode, suppressing or masking echo per q's settings and offering
+// TAB completion via q.Complete. message is the prompt text, reprinted
+// after a double-TAB candidate listing so the buffer isn't left stranded
+// below it. It reports ok=false when stdin isn't a TTY (or can't be put
+// in raw mode), so the caller can fall back to plain buffered reads.
+func readLineRaw(q *Question, message string) (line string, ok bool, err error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", false, nil
+	}
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", false, nil
+	}
+	defer func() {
+		term.Restore(fd, oldState)
+	}()
+
+	var buf []byte
+	var lastWasTab bool
+	one := make([]byte, 1)
+	for {
+		n, rerr := os.Stdin.Read(one)
+		if rerr != nil {
+			return "", true, rerr
+		}
+		if n == 0 {
+			continue
+		}
+		c := one[0]
+		if c != 9 {
+			lastWasTab = false
+		}
+		switch {
+		case c == '\r' || c == '\n':
+			fmt.Print("\r\n")
+			return string(buf), true, nil
+		case c == 3: // Ctrl-C
+			return "", true, fmt.Errorf("goline: interrupted")
+		case c == 127 || c == 8: // DEL or backspace
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+				if q.Echo || q.EchoMask != 0 {
+					fmt.Print("\b \b")
+				}
+			}
+		case c == 9: // Tab
+			if q.Complete == nil {
+				continue
+			}
+			candidates := q.Complete(string(buf))
+			if len(candidates) == 0 {
+				continue
+			}
+			if lcp := longestCommonPrefix(candidates); len(lcp) > len(buf) {
+				for _, d := range []byte(lcp[len(buf):]) {
+					buf = append(buf, d)
+					echoChar(q, d)
+				}
+				lastWasTab = false
+				continue
+			}
+			if lastWasTab {
+				fmt.Print("\r\n")
+				for _, row := range columnRows(candidates, 80, false) {
+					fmt.Print(strings.TrimRight(row, " "), "\r\n")
+				}
+				fmt.Print(message, "\r\n")
+				for _, d := range buf {
+					echoChar(q, d)
+				}
+				lastWasTab = false
+			} else {
+				lastWasTab = true
+			}
+		case c < 0x20:
+			// Ignore other control characters.
+		default:
+			buf = append(buf, c)
+			echoChar(q, c)
+		}
+	}
+}
+
+// longestCommonPrefix returns the longest string that prefixes every
+// element of strs. It returns "" for an empty slice.
+func longestCommonPrefix(strs []string) string {
+	if len(strs) == 0 {
+		return ""
+	}
+	prefix := strs[0]
+	for _, s := range strs[1:] {
+		for len(prefix) > 0 && !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+		if prefix == "" {
+			break
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,47 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestValidateRunsWithoutLeakingViaIn confirms that a Question.Validate
+// hook, unlike In, can reject an answer without echoing the expected
+// value back into the rejection message - the property needed to confirm
+// a Password prompt against a previous one.
+func TestValidateRunsWithoutLeakingViaIn(t *testing.T) {
+	old := os.Stdin
+	defer func() { os.Stdin = old }()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %s", err)
+	}
+	os.Stdin = r
+	w.WriteString("wrong\nhunter2\n")
+	w.Close()
+
+	const first = "hunter2"
+	var dest string
+	out := captureStdout(t, func() {
+		Ask(&dest, "Confirm password: ", func(q *Question) {
+			q.Validate(func(q *Question, s string) error {
+				if s != first {
+					return fmt.Errorf("goline: passwords do not match")
+				}
+				return nil
+			})
+		})
+	})
+	if dest != first {
+		t.Fatalf("dest = %q, want %q", dest, first)
+	}
+	if strings.Contains(out, first) {
+		t.Fatalf("output %q leaks the password %q; Validate's rejection message must not embed the expected value the way In's does", out, first)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import (
+	"bufio"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Validator checks a raw (unparsed) answer string, returning a non-nil
+// error if the answer should be rejected and re-prompted for.
+type Validator func(q *Question, s string) error
+
+// Question carries the configuration for a single Ask (or Ask-derived)
+// prompt: its text, default, validators, and the parsed answer once one
+// has been accepted.
+type Question struct {
+	Question  string
+	Default   string
+	Panic     func(error)
+	Responses map[MessageID]string
+
+	// Catalog and Language override the package-level defaults (set via
+	// SetCatalog/SetLanguage) for this Question only.
+	Catalog  *Catalog
+	Language language.Tag
+
+	// Echo controls whether typed characters are echoed to the terminal.
+	// It is true by default; Password sets it to false.
+	Echo bool
+
+	// EchoMask, if non-zero, is echoed once per typed character instead of
+	// the character itself (e.g. '*'), rather than suppressing echo
+	// entirely.
+	EchoMask rune
+
+	// PageSize caps how many Select/MultiSelect items are visible at once.
+	// Zero means show every item with no paging.
+	PageSize int
+
+	// Editor escalates an Ask on a *string to the $EDITOR-backed prompt
+	// (see Editor) whenever the typed response is empty or equals
+	// EditorTrigger. Editor(...) sets this for you.
+	Editor bool
+
+	// EditorTrigger is the sentinel response, besides an empty one, that
+	// escalates to the editor. Editor(...) defaults it to "e".
+	EditorTrigger string
+
+	// Complete, if set, offers tab-completion candidates for the current
+	// buffer (the text typed so far). A single TAB inserts the longest
+	// common prefix of the candidates; a second TAB lists them all. See
+	// StringSetCompleter and FileCompleter for ready-made completers.
+	Complete func(prefix string) []string
+
+	t          Type
+	validators []Validator
+	val        interface{}
+}
+
+func newQuestion(t Type) *Question {
+	return &Question{
+		Responses: map[MessageID]string{},
+		Echo:      true,
+		t:         t,
+	}
+}
+
+// In restricts acceptable answers to the members of set.
+func (q *Question) In(set StringSet) *Question {
+	q.validators = append(q.validators, func(q *Question, s string) error {
+		if !set.Contains(s) {
+			return fmt.Errorf("goline: %q is not one of %v", s, []string(set))
+		}
+		return nil
+	})
+	return q
+}
+
+// Validate registers v as an additional check on the raw answer, run in
+// the order added alongside In's. Unlike In, v controls its own rejection
+// message, so it can reject without echoing the answer back (e.g.
+// confirming a Password prompt against a previous one without leaking it
+// into the terminal on a mismatch).
+func (q *Question) Validate(v Validator) *Question {
+	q.validators = append(q.validators, v)
+	return q
+}
+
+func (q *Question) defaultString(tail string) string {
+	if q.Default == "" {
+		return tail
+	}
+	return msg(q, DefaultPrefix, "|%s|%s", q.Default, tail)
+}
+
+// tryFirstAnswer is a hook for answering a Question without prompting
+// (e.g. a pre-recorded answer supplied by the caller). Nothing currently
+// populates one, so it always fails over to the interactive prompt.
+func (q *Question) tryFirstAnswer() error {
+	return fmt.Errorf("goline: no first answer")
+}
+
+func (q *Question) parse(s string) error {
+	if s == "" {
+		s = q.Default
+	}
+	for _, v := range q.validators {
+		if err := v(q, s); err != nil {
+			return err
+		}
+	}
+	switch q.t {
+	case String:
+		q.val = s
+	case Int:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 0, 64)
+		if err != nil {
+			return err
+		}
+		q.val = n
+	case Uint:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 0, 64)
+		if err != nil {
+			return err
+		}
+		q.val = n
+	case Float:
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return err
+		}
+		q.val = n
+	default:
+		return fmt.Errorf("goline: unknown Type %d", q.t)
+	}
+	return nil
+}
+
+// setDest casts q.val, which is always parsed into a wide Go type, down
+// into the narrower type dest actually points at.
+func (q *Question) setDest(dest interface{}) error {
+	v := reflect.ValueOf(dest).Elem()
+	switch val := q.val.(type) {
+	case string:
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("goline: cannot assign string to %s", v.Kind())
+		}
+		v.SetString(val)
+	case int64:
+		if v.Kind() < reflect.Int || v.Kind() > reflect.Int64 {
+			return fmt.Errorf("goline: cannot assign int to %s", v.Kind())
+		}
+		v.SetInt(val)
+	case uint64:
+		if v.Kind() < reflect.Uint || v.Kind() > reflect.Uint64 {
+			return fmt.Errorf("goline: cannot assign uint to %s", v.Kind())
+		}
+		v.SetUint(val)
+	case float64:
+		if v.Kind() != reflect.Float32 && v.Kind() != reflect.Float64 {
+			return fmt.Errorf("goline: cannot assign float to %s", v.Kind())
+		}
+		v.SetFloat(val)
+	default:
+		return fmt.Errorf("goline: no parsed value to assign")
+	}
+	return nil
+}
+
+// readLine reads one line of input for q, honoring Echo/EchoMask by
+// falling back to raw terminal mode when the terminal would otherwise
+// echo characters q wants hidden or masked. message is the prompt text
+// last shown to the user, so raw mode can redraw it if needed.
+func (q *Question) readLine(r *bufio.Reader, message string) (string, error) {
+	if q.wantsRawIO() {
+		if line, ok, err := readLineRaw(q, message); ok {
+			return line, err
+		}
+	}
+	return readLineBuffered(r)
+}
+
+func readLineBuffered(r *bufio.Reader) (string, error) {
+	var resp []byte
+	for cont := true; cont; {
+		s, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		resp = append(resp, s...)
+		cont = isPrefix
+	}
+	return string(resp), nil
+}
+
+func panicUnrecoverable(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
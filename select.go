@@ -0,0 +1,304 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// escSeqTimeout bounds how long readSelectKey waits for the remaining
+// bytes of an arrow-key escape sequence once it has seen a lone ESC (27).
+// Without it, a standalone Esc keypress (meant to cancel the menu) would
+// block forever waiting for a '[' that's never coming.
+const escSeqTimeout = 50 * time.Millisecond
+
+// Select prompts the user to choose one item from items (a Slice of string
+// or Stringer values, as accepted by List) using an arrow-key menu. dest
+// must be a *int (the chosen index), *string, or *Stringer. When stdin
+// isn't a TTY it falls back to printing a numbered list and reading an
+// index via Ask, just like Ask itself would.
+func Select(dest interface{}, message string, items interface{}, config func(*Question)) error {
+	q := newQuestion(String)
+	if config != nil {
+		config(q)
+	}
+	strs := stringifyList(items)
+	if len(strs) == 0 {
+		return fmt.Errorf("goline: Select given no items")
+	}
+	idxs, err := runSelectMenu(message, strs, false, q.PageSize)
+	if err != nil {
+		if q.Panic != nil {
+			q.Panic(err)
+			return nil
+		}
+		return err
+	}
+	return assignSelected(dest, items, idxs[0])
+}
+
+// MultiSelect prompts the user to toggle any number of items on or off
+// using the same arrow-key menu as Select, confirming with enter. dest
+// must be a pointer to a slice of int, string, or Stringer.
+func MultiSelect(dest interface{}, message string, items interface{}, config func(*Question)) error {
+	q := newQuestion(String)
+	if config != nil {
+		config(q)
+	}
+	strs := stringifyList(items)
+	if len(strs) == 0 {
+		return fmt.Errorf("goline: MultiSelect given no items")
+	}
+	idxs, err := runSelectMenu(message, strs, true, q.PageSize)
+	if err != nil {
+		if q.Panic != nil {
+			q.Panic(err)
+			return nil
+		}
+		return err
+	}
+	return assignMultiSelected(dest, items, idxs)
+}
+
+func assignSelected(dest interface{}, items interface{}, idx int) error {
+	item := reflect.ValueOf(items).Index(idx).Interface()
+	switch d := dest.(type) {
+	case *int:
+		*d = idx
+	case *string:
+		*d = makeStringer(item).String()
+	case *Stringer:
+		*d = makeStringer(item)
+	default:
+		return fmt.Errorf("goline: Select destination must be *int, *string, or *Stringer, not %T", dest)
+	}
+	return nil
+}
+
+func assignMultiSelected(dest interface{}, items interface{}, idxs []int) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("goline: MultiSelect destination must be a pointer to a slice, not %T", dest)
+	}
+	ival := reflect.ValueOf(items)
+	elemType := dv.Elem().Type().Elem()
+	out := reflect.MakeSlice(dv.Elem().Type(), 0, len(idxs))
+	for _, idx := range idxs {
+		item := ival.Index(idx).Interface()
+		switch {
+		case elemType.Kind() == reflect.Int:
+			out = reflect.Append(out, reflect.ValueOf(idx))
+		case elemType.Kind() == reflect.String:
+			out = reflect.Append(out, reflect.ValueOf(makeStringer(item).String()))
+		case elemType.Kind() == reflect.Interface && elemType.Implements(reflect.TypeOf((*Stringer)(nil)).Elem()):
+			out = reflect.Append(out, reflect.ValueOf(makeStringer(item)))
+		default:
+			return fmt.Errorf("goline: MultiSelect destination slice element type %s unsupported", elemType)
+		}
+	}
+	dv.Elem().Set(out)
+	return nil
+}
+
+// selectKey is a single logical keypress recognized by the menu reader.
+type selectKey int
+
+const (
+	keyOther selectKey = iota
+	keyUp
+	keyDown
+	keySpace
+	keyEnter
+	keyEsc
+)
+
+func readSelectKey() (selectKey, error) {
+	one := make([]byte, 1)
+	if _, err := os.Stdin.Read(one); err != nil {
+		return keyOther, err
+	}
+	switch one[0] {
+	case '\r', '\n':
+		return keyEnter, nil
+	case ' ':
+		return keySpace, nil
+	case 'j':
+		return keyDown, nil
+	case 'k':
+		return keyUp, nil
+	case 3:
+		return keyEsc, fmt.Errorf("goline: interrupted")
+	case 27: // ESC, possibly the start of an arrow-key sequence.
+		return readEscSequence()
+	default:
+		return keyOther, nil
+	}
+}
+
+// readEscSequence reads the byte(s) following a lone ESC (27), returning
+// keyUp/keyDown for a recognized arrow-key escape sequence ("\x1b[A" /
+// "\x1b[B") and keyEsc otherwise. Where os.Stdin accepts a read deadline,
+// it bounds each read with escSeqTimeout so a standalone Esc keypress -
+// which never sends a follow-up byte - resolves to keyEsc promptly. Where
+// SetReadDeadline fails, the reads block until the next byte the user
+// types.
+func readEscSequence() (selectKey, error) {
+	deadline := time.Now().Add(escSeqTimeout)
+	hasDeadline := os.Stdin.SetReadDeadline(deadline) == nil
+	if hasDeadline {
+		defer os.Stdin.SetReadDeadline(time.Time{})
+	}
+
+	seq := make([]byte, 1)
+	if _, err := os.Stdin.Read(seq); err != nil || seq[0] != '[' {
+		return keyEsc, nil
+	}
+	if _, err := os.Stdin.Read(seq); err != nil {
+		return keyEsc, nil
+	}
+	switch seq[0] {
+	case 'A':
+		return keyUp, nil
+	case 'B':
+		return keyDown, nil
+	}
+	return keyOther, nil
+}
+
+// runSelectMenu drives the interactive arrow-key menu shared by Select and
+// MultiSelect, returning the chosen indices into items. For MultiSelect,
+// space toggles the highlighted item and enter confirms the whole set; for
+// Select, enter immediately picks the highlighted item.
+func runSelectMenu(message string, items []string, multi bool, pageSize int) ([]int, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return selectMenuFallback(message, items, multi)
+	}
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return selectMenuFallback(message, items, multi)
+	}
+	defer func() {
+		term.Restore(fd, oldState)
+	}()
+
+	if pageSize <= 0 || pageSize > len(items) {
+		pageSize = len(items)
+	}
+	cursor, top := 0, 0
+	chosen := make(map[int]bool)
+
+	draw := func(first bool) {
+		if !first {
+			fmt.Printf("\x1b[%dA", pageSize)
+		}
+		for i := 0; i < pageSize; i++ {
+			idx := top + i
+			prefix := "  "
+			if idx == cursor {
+				prefix = "> "
+			}
+			fmt.Print("\x1b[2K\r")
+			if multi {
+				mark := " "
+				if chosen[idx] {
+					mark = "x"
+				}
+				fmt.Printf("%s[%s] %s\r\n", prefix, mark, items[idx])
+			} else {
+				fmt.Printf("%s%s\r\n", prefix, items[idx])
+			}
+		}
+	}
+
+	Say(message)
+	draw(true)
+	for {
+		k, err := readSelectKey()
+		if err != nil {
+			return nil, err
+		}
+		switch k {
+		case keyUp:
+			if cursor > 0 {
+				cursor--
+				if cursor < top {
+					top = cursor
+				}
+				draw(false)
+			}
+		case keyDown:
+			if cursor < len(items)-1 {
+				cursor++
+				if cursor >= top+pageSize {
+					top = cursor - pageSize + 1
+				}
+				draw(false)
+			}
+		case keySpace:
+			if multi {
+				chosen[cursor] = !chosen[cursor]
+				draw(false)
+			}
+		case keyEnter:
+			if !multi {
+				return []int{cursor}, nil
+			}
+			var idxs []int
+			for i := range items {
+				if chosen[i] {
+					idxs = append(idxs, i)
+				}
+			}
+			return idxs, nil
+		case keyEsc:
+			return nil, fmt.Errorf("goline: selection canceled")
+		}
+	}
+}
+
+// selectMenuFallback handles non-TTY stdin by printing a numbered list and
+// reading a plain-text response, matching Ask's own non-interactive
+// behavior.
+func selectMenuFallback(message string, items []string, multi bool) ([]int, error) {
+	Say(message)
+	for i, item := range items {
+		SayTrimmed(fmt.Sprintf("%d) %s", i+1, item))
+	}
+	if !multi {
+		var n int
+		if err := Ask(&n, "? ", func(q *Question) { q.Default = "1" }); err != nil {
+			return nil, err
+		}
+		if n < 1 || n > len(items) {
+			return nil, fmt.Errorf("goline: selection %d out of range", n)
+		}
+		return []int{n - 1}, nil
+	}
+
+	var resp string
+	if err := Ask(&resp, "? (comma-separated) ", nil); err != nil {
+		return nil, err
+	}
+	var idxs []int
+	for _, field := range strings.FieldsFunc(resp, func(r rune) bool { return r == ',' || r == ' ' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("goline: invalid selection %q", field)
+		}
+		if n < 1 || n > len(items) {
+			return nil, fmt.Errorf("goline: selection %d out of range", n)
+		}
+		idxs = append(idxs, n-1)
+	}
+	return idxs, nil
+}
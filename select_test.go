@@ -0,0 +1,25 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+import "testing"
+
+type selectTestStringer struct{ s string }
+
+func (s selectTestStringer) String() string { return s.s }
+
+func TestAssignMultiSelectedStringerSlice(t *testing.T) {
+	items := []selectTestStringer{{"a"}, {"b"}, {"c"}}
+	var dest []Stringer
+	if err := assignMultiSelected(&dest, items, []int{0, 2}); err != nil {
+		t.Fatalf("assignMultiSelected: %s", err)
+	}
+	if len(dest) != 2 {
+		t.Fatalf("len(dest) = %d, want 2", len(dest))
+	}
+	if dest[0].String() != "a" || dest[1].String() != "c" {
+		t.Fatalf("dest = %v, want [a c]", dest)
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+// StringSet is an unordered collection of acceptable string answers, used
+// with Question.In to restrict a response to one of a fixed vocabulary
+// (e.g. the "yes"/"no" family Confirm validates against).
+type StringSet []string
+
+// Contains reports whether s is a member of the set.
+func (set StringSet) Contains(s string) bool {
+	for _, member := range set {
+		if member == s {
+			return true
+		}
+	}
+	return false
+}
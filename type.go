@@ -0,0 +1,15 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goline
+
+// Type describes the kind of value a Question parses its answer into.
+type Type uint
+
+const (
+	String Type = iota
+	Int
+	Uint
+	Float
+)